@@ -0,0 +1,52 @@
+package manager
+
+import (
+	"context"
+	"os"
+)
+
+// Env holds the traffic manager's process configuration, populated once at
+// startup from the environment.
+type Env struct {
+	ServerHost string
+	ServerPort string
+
+	// EnableRESTGateway turns on the grpc-gateway mux that exposes the
+	// Manager service as HTTP+JSON under restGatewayPrefix.
+	EnableRESTGateway bool
+
+	// EnableTwirp turns on the Twirp transport mounted under twirpPrefix.
+	EnableTwirp bool
+
+	// SystemaHosts is a comma-separated list of "host:port" SystemA
+	// endpoints to fail over across, as consumed by newSystemaPool.
+	SystemaHosts string
+}
+
+// LoadEnv reads the traffic manager's configuration from the process
+// environment.
+func LoadEnv(ctx context.Context) (*Env, error) {
+	return &Env{
+		ServerHost:        os.Getenv("SERVER_HOST"),
+		ServerPort:        envOrDefault("SERVER_PORT", "8081"),
+		EnableRESTGateway: envIsTrue("ENABLE_REST_GATEWAY"),
+		EnableTwirp:       envIsTrue("ENABLE_TWIRP"),
+		SystemaHosts:      os.Getenv("SYSTEMA_HOSTS"),
+	}, nil
+}
+
+func envOrDefault(key, def string) string {
+	if v := os.Getenv(key); v != "" {
+		return v
+	}
+	return def
+}
+
+func envIsTrue(key string) bool {
+	switch os.Getenv(key) {
+	case "1", "true", "TRUE", "True":
+		return true
+	default:
+		return false
+	}
+}
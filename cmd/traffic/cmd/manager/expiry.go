@@ -0,0 +1,139 @@
+package manager
+
+import (
+	"container/heap"
+	"sync"
+	"time"
+)
+
+// expiryWheel is a min-heap of deadline entries keyed on next-expiry
+// timestamp, replacing the old fixed 5-second poll of every tracked entity.
+// stateWatcher inserts a deadline per agent and intercept as it observes them
+// on their watch streams, refreshing an agent's on every agent snapshot and an
+// intercept's on every snapshot that carries an update for it, and the gc
+// goroutine sleeps precisely until the earliest deadline instead of scanning
+// everything on a timer. Client/session expiry isn't tracked here: there's no
+// client registration concept in this package to hang a deadline off of.
+type expiryWheel struct {
+	mu      sync.Mutex
+	entries map[string]*expiryEntry
+	heap    expiryHeap
+}
+
+// expiryEntry tracks one tracked entity's (agent or intercept) next
+// expiry deadline. index is maintained by container/heap for O(log n)
+// updates.
+type expiryEntry struct {
+	key      string
+	deadline time.Time
+	index    int
+}
+
+func newExpiryWheel() *expiryWheel {
+	return &expiryWheel{entries: make(map[string]*expiryEntry)}
+}
+
+// touch inserts a new deadline for key, or updates it in place if key is
+// already tracked. This backs both an entity's initial insert and subsequent
+// keepalives/snapshot refreshes.
+func (w *expiryWheel) touch(key string, deadline time.Time) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if e, ok := w.entries[key]; ok {
+		e.deadline = deadline
+		heap.Fix(&w.heap, e.index)
+		return
+	}
+	e := &expiryEntry{key: key, deadline: deadline}
+	w.entries[key] = e
+	heap.Push(&w.heap, e)
+}
+
+// remove drops key from the wheel, e.g. once its entity has been explicitly
+// removed rather than left to expire.
+func (w *expiryWheel) remove(key string) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	e, ok := w.entries[key]
+	if !ok {
+		return
+	}
+	heap.Remove(&w.heap, e.index)
+	delete(w.entries, key)
+}
+
+// next returns the key and deadline of the earliest-expiring entry, and
+// whether the wheel holds any entries at all.
+func (w *expiryWheel) next() (string, time.Time, bool) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if len(w.heap) == 0 {
+		return "", time.Time{}, false
+	}
+	e := w.heap[0]
+	return e.key, e.deadline, true
+}
+
+// expired pops and returns the keys of every entry whose deadline is at or
+// before now, leaving later entries untouched.
+func (w *expiryWheel) expired(now time.Time) []string {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	var keys []string
+	for len(w.heap) > 0 && !w.heap[0].deadline.After(now) {
+		e := heap.Pop(&w.heap).(*expiryEntry)
+		delete(w.entries, e.key)
+		keys = append(keys, e.key)
+	}
+	return keys
+}
+
+// isEmpty reports whether the wheel currently holds no entries. The gc
+// goroutine does not call it; it relies on dueForSweep and waitDuration.
+func (w *expiryWheel) isEmpty() bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return len(w.heap) == 0
+}
+
+// expiryHeap implements container/heap.Interface over *expiryEntry, ordered
+// by ascending deadline.
+type expiryHeap []*expiryEntry
+
+func (h expiryHeap) Len() int           { return len(h) }
+func (h expiryHeap) Less(i, j int) bool { return h[i].deadline.Before(h[j].deadline) }
+func (h expiryHeap) Swap(i, j int)      { h[i], h[j] = h[j], h[i]; h[i].index, h[j].index = i, j }
+
+func (h *expiryHeap) Push(x interface{}) {
+	e := x.(*expiryEntry)
+	e.index = len(*h)
+	*h = append(*h, e)
+}
+
+func (h *expiryHeap) Pop() interface{} {
+	old := *h
+	n := len(old)
+	e := old[n-1]
+	old[n-1] = nil
+	e.index = -1
+	*h = old[:n-1]
+	return e
+}
+
+// waitDuration returns how long the gc goroutine should sleep before the
+// next deadline fires. It never returns a negative duration: an
+// already-past deadline sleeps 0, so expire() runs immediately.
+func (w *expiryWheel) waitDuration(now time.Time) (time.Duration, bool) {
+	_, deadline, ok := w.next()
+	if !ok {
+		return 0, false
+	}
+	if d := deadline.Sub(now); d > 0 {
+		return d, true
+	}
+	return 0, true
+}
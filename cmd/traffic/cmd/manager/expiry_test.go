@@ -0,0 +1,64 @@
+package manager
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+func BenchmarkExpiryWheelTouch(b *testing.B) {
+	w := newExpiryWheel()
+	now := time.Now()
+	for i := 0; i < 10_000; i++ {
+		w.touch(fmt.Sprintf("intercept-%d", i), now.Add(time.Duration(i)*time.Millisecond))
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		key := fmt.Sprintf("intercept-%d", i%10_000)
+		w.touch(key, now.Add(time.Duration(i)*time.Millisecond))
+	}
+}
+
+func TestDueForSweepDrainsWheel(t *testing.T) {
+	w := newExpiryWheel()
+	now := time.Now()
+	w.touch("agent:a1", now.Add(-time.Second))
+	w.touch("agent:a2", now.Add(-time.Second))
+	w.touch("intercept:i1", now.Add(time.Hour))
+
+	if n := dueForSweep(w, now); n != 2 {
+		t.Fatalf("dueForSweep() = %d, want 2 (only the two past-deadline entries)", n)
+	}
+	if w.isEmpty() {
+		t.Fatal("dueForSweep popped the not-yet-due entry too")
+	}
+	if n := dueForSweep(w, now); n != 0 {
+		t.Fatalf("dueForSweep() on a second call = %d, want 0 (already-swept entries must not be counted twice)", n)
+	}
+
+	if n := dueForSweep(w, now.Add(2*time.Hour)); n != 1 {
+		t.Fatalf("dueForSweep() after the remaining deadline passed = %d, want 1", n)
+	}
+	if !w.isEmpty() {
+		t.Fatal("wheel should be empty once every entry has been swept")
+	}
+}
+
+func BenchmarkExpiryWheelExpired(b *testing.B) {
+	now := time.Now()
+
+	for i := 0; i < b.N; i++ {
+		b.StopTimer()
+		w := newExpiryWheel()
+		for j := 0; j < 10_000; j++ {
+			w.touch(fmt.Sprintf("intercept-%d", j), now.Add(-time.Second))
+		}
+		b.StartTimer()
+
+		keys := w.expired(now)
+		if len(keys) != 10_000 {
+			b.Fatalf("expected 10000 expired entries, got %d", len(keys))
+		}
+	}
+}
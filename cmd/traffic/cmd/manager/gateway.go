@@ -0,0 +1,28 @@
+package manager
+
+import (
+	"context"
+
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+
+	rpc "github.com/telepresenceio/telepresence/rpc/v2/manager"
+)
+
+// restGatewayPrefix is the path prefix under which the grpc-gateway mux is
+// mounted on the h2c handler, alongside gRPC and the plain HTTP fallback.
+const restGatewayPrefix = "/api/"
+
+// grpcGatewayMux dials the Manager's own gRPC endpoint in-process and
+// returns a grpc-gateway mux that translates HTTP+JSON requests into the
+// equivalent ManagerServer RPCs, so non-Go clients and browser tooling can
+// drive the Manager without a gRPC client library.
+func grpcGatewayMux(ctx context.Context, endpoint string) (*runtime.ServeMux, error) {
+	dopts := []grpc.DialOption{grpc.WithTransportCredentials(insecure.NewCredentials())}
+	mux := runtime.NewServeMux()
+	if err := rpc.RegisterManagerHandlerFromEndpoint(ctx, mux, endpoint, dopts); err != nil {
+		return nil, err
+	}
+	return mux, nil
+}
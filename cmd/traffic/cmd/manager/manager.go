@@ -2,12 +2,17 @@ package manager
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net/http"
 	"os"
 	"strings"
 	"time"
 
+	grpcmw "github.com/grpc-ecosystem/go-grpc-middleware"
+	grpcprometheus "github.com/grpc-ecosystem/go-grpc-prometheus"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"golang.org/x/net/http2"
 	"golang.org/x/net/http2/h2c"
 	"google.golang.org/grpc"
@@ -17,9 +22,28 @@ import (
 	"github.com/datawire/dlib/dlog"
 	"github.com/datawire/dlib/dutil"
 	rpc "github.com/telepresenceio/telepresence/rpc/v2/manager"
+	"github.com/telepresenceio/telepresence/v2/pkg/grpc/interceptors"
 	"github.com/telepresenceio/telepresence/v2/pkg/version"
 )
 
+// metricsPath is the path at which Prometheus scrapes the manager, routed
+// alongside gRPC and the REST gateway on the same h2c handler.
+const metricsPath = "/metrics"
+
+// accessLogUnaryInterceptor logs each unary RPC's method and outcome through
+// dlog, so per-RPC access logging respects the same context-scoped log level
+// as the rest of the manager instead of a second, parallel logging pipeline
+// writing unstructured lines straight to stderr.
+func accessLogUnaryInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	resp, err := handler(ctx, req)
+	if err != nil {
+		dlog.Errorf(ctx, "rpc %s: %v", info.FullMethod, err)
+	} else {
+		dlog.Debugf(ctx, "rpc %s: ok", info.FullMethod)
+	}
+	return resp, err
+}
+
 func Main(ctx context.Context, args ...string) error {
 	dlog.Infof(ctx, "Traffic Manager %s [pid:%d]", version.Version, os.Getpid())
 
@@ -32,23 +56,64 @@ func Main(ctx context.Context, args ...string) error {
 		EnableSignalHandling: true,
 	})
 	mgr := NewManager(ctx, env)
+	if env.SystemaHosts != "" {
+		mgr.systema = newSystemaPool(env.SystemaHosts)
+	}
+	mets := newMetrics(prometheus.DefaultRegisterer)
+
+	wheel := newExpiryWheel()
+	sw := newStateWatcher(wheel, mets)
+	g.Go("state-watch", func(ctx context.Context) error {
+		sw.run(ctx, mgr)
+		return nil
+	})
 
 	// Serve HTTP (including gRPC)
 	g.Go("httpd", func(ctx context.Context) error {
 		host := env.ServerHost
 		port := env.ServerPort
 
-		grpcHandler := grpc.NewServer()
+		grpcHandler := grpc.NewServer(
+			grpcmw.WithUnaryServerChain(
+				grpcprometheus.UnaryServerInterceptor,
+				accessLogUnaryInterceptor,
+				interceptors.UnaryServerInterceptor,
+			),
+			grpc.StreamInterceptor(interceptors.StreamServerInterceptor),
+		)
 		httpHandler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			fmt.Fprintf(w, "Hello World from: %s\n", r.URL.Path)
 		}))
+		metricsHandler := promhttp.Handler()
+
+		var gatewayMux http.Handler
+		if env.EnableRESTGateway {
+			mux, err := grpcGatewayMux(ctx, host+":"+port)
+			if err != nil {
+				return fmt.Errorf("failed to start REST gateway: %w", err)
+			}
+			gatewayMux = mux
+		}
+
+		var twirpHandler http.Handler
+		if env.EnableTwirp {
+			twirpHandler = newTwirpHandler(mgr, sw)
+		}
+
 		server := &http.Server{
 			Addr:     host + ":" + port,
 			ErrorLog: dlog.StdLogger(ctx, dlog.LogLevelError),
 			Handler: h2c.NewHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-				if r.ProtoMajor == 2 && strings.HasPrefix(r.Header.Get("Content-Type"), "application/grpc") {
+				switch {
+				case r.ProtoMajor == 2 && strings.HasPrefix(r.Header.Get("Content-Type"), "application/grpc"):
 					grpcHandler.ServeHTTP(w, r)
-				} else {
+				case r.URL.Path == metricsPath:
+					metricsHandler.ServeHTTP(w, r)
+				case gatewayMux != nil && strings.HasPrefix(r.URL.Path, restGatewayPrefix):
+					gatewayMux.ServeHTTP(w, r)
+				case twirpHandler != nil && strings.HasPrefix(r.URL.Path, twirpPrefix):
+					twirpHandler.ServeHTTP(w, r)
+				default:
 					httpHandler.ServeHTTP(w, r)
 				}
 			}), &http2.Server{}),
@@ -56,25 +121,49 @@ func Main(ctx context.Context, args ...string) error {
 
 		rpc.RegisterManagerServer(grpcHandler, mgr)
 		grpc_health_v1.RegisterHealthServer(grpcHandler, &HealthChecker{})
+		grpcprometheus.Register(grpcHandler)
 
 		return dutil.ListenAndServeHTTPWithContext(ctx, server)
 	})
 
 	g.Go("intercept-gc", func(ctx context.Context) error {
-		// Loop calling Expire
-		ticker := time.NewTicker(5 * time.Second)
-		defer ticker.Stop()
+		// Sleep precisely until the next deadline in wheel instead of polling
+		// on a fixed interval; fall back to a conservative cap when the wheel
+		// is empty so a newly-added entry isn't kept waiting forever.
+		const idleWait = 5 * time.Second
+
+		timer := time.NewTimer(idleWait)
+		defer timer.Stop()
 
 		for {
 			select {
-			case <-ticker.C:
-				mgr.expire()
+			case <-timer.C:
+				now := time.Now()
+				if n := dueForSweep(wheel, now); n > 0 {
+					mgr.expire()
+					mets.interceptGCSweeps.Inc()
+				}
+
+				wait := idleWait
+				if d, ok := wheel.waitDuration(now); ok {
+					wait = d
+				}
+				timer.Reset(wait)
 			case <-ctx.Done():
 				return nil
 			}
 		}
 	})
 
+	if pool, ok := mgr.systema.(*systemaPool); ok {
+		g.Go("systema-health", func(ctx context.Context) error {
+			pool.healthCheck(ctx)
+			return nil
+		})
+	} else {
+		dlog.Infof(ctx, "systema: client pool doesn't support multi-endpoint failover, skipping health checker")
+	}
+
 	// This goroutine is responsible for informing System A of intercepts (and
 	// relevant metadata like domains) that have been garbage collected. This
 	// ensures System A doesn't list preview URLs + intercepts that no longer
@@ -92,15 +181,41 @@ func Main(ctx context.Context, args ...string) error {
 						// First we remove the PreviewDomain if it exists
 						if update.Value.PreviewDomain != "" {
 							err = mgr.reapDomain(ctx, sa, update)
+							if err != nil && isConnDown(err) {
+								evictConn(mgr.systema, sa)
+								if retrySA, retryErr := mgr.systema.Get(); retryErr == nil {
+									sa = retrySA
+									err = mgr.reapDomain(ctx, sa, update)
+								}
+							}
 							if err != nil {
 								dlog.Errorln(ctx, "systema: remove domain:", err)
+							} else {
+								mets.previewDomainReap.Inc()
 							}
 						}
 						// Now we inform SystemA of the intercepts removal
 						dlog.Debugf(ctx, "systema: remove intercept: %q", update.Value.Id)
 						err = mgr.reapIntercept(ctx, sa, update)
-						if err != nil {
+						if err != nil && isConnDown(err) {
+							evictConn(mgr.systema, sa)
+							if retrySA, retryErr := mgr.systema.Get(); retryErr == nil {
+								sa = retrySA
+								err = mgr.reapIntercept(ctx, sa, update)
+							}
+						}
+						switch {
+						case err == nil:
+							mets.systemaReapOK.Inc()
+						case errors.Is(err, interceptors.ErrInterceptGone):
+							// Already deleted on SystemA's side: nothing left to do.
+							mets.systemaReapOK.Inc()
+						case errors.Is(err, interceptors.ErrSystemAAuth):
+							dlog.Errorln(ctx, "systema: remove intercept: authentication failed:", err)
+							mets.systemaReapFailed.Inc()
+						default:
 							dlog.Errorln(ctx, "systema: remove intercept:", err)
+							mets.systemaReapFailed.Inc()
 						}
 
 						// Release the connection we got to delete the domain + intercept
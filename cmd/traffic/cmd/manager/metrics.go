@@ -0,0 +1,57 @@
+package manager
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// metrics holds the custom Prometheus collectors for domain-specific counters
+// that the manager tracks alongside the generic per-RPC metrics contributed
+// by grpc_prometheus.
+type metrics struct {
+	agentsConnected   prometheus.Gauge
+	interceptsActive  *prometheus.GaugeVec
+	interceptGCSweeps prometheus.Counter
+	systemaReapOK     prometheus.Counter
+	systemaReapFailed prometheus.Counter
+	previewDomainReap prometheus.Counter
+}
+
+// newMetrics creates and registers the manager's custom collectors against
+// the given registerer.
+func newMetrics(reg prometheus.Registerer) *metrics {
+	m := &metrics{
+		agentsConnected: prometheus.NewGauge(prometheus.GaugeOpts{
+			Name: "tm_agents_connected",
+			Help: "Number of agents currently connected to the traffic manager.",
+		}),
+		interceptsActive: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "tm_intercepts_active",
+			Help: "Number of active intercepts, by disposition.",
+		}, []string{"disposition"}),
+		interceptGCSweeps: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "tm_intercept_gc_sweeps_total",
+			Help: "Number of intercept garbage collection sweeps performed.",
+		}),
+		systemaReapOK: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "tm_systema_reap_success_total",
+			Help: "Number of successful SystemA reap notifications.",
+		}),
+		systemaReapFailed: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "tm_systema_reap_failed_total",
+			Help: "Number of failed SystemA reap notifications.",
+		}),
+		previewDomainReap: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "tm_preview_domain_reaps_total",
+			Help: "Number of preview domains reaped from SystemA.",
+		}),
+	}
+	reg.MustRegister(
+		m.agentsConnected,
+		m.interceptsActive,
+		m.interceptGCSweeps,
+		m.systemaReapOK,
+		m.systemaReapFailed,
+		m.previewDomainReap,
+	)
+	return m
+}
@@ -0,0 +1,220 @@
+package manager
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+
+	"github.com/datawire/dlib/dlog"
+	"github.com/telepresenceio/telepresence/v2/pkg/grpc/interceptors"
+)
+
+// systemaUnhealthyCooldown is how long an endpoint that was evicted for
+// being down is skipped before it is retried by the health checker.
+const systemaUnhealthyCooldown = 30 * time.Second
+
+// systemaEndpoint tracks the dial target and health state for one of the
+// configured SystemA hosts.
+type systemaEndpoint struct {
+	addr      string
+	conn      *grpc.ClientConn
+	unhealthy bool
+	evictedAt time.Time
+}
+
+// systemaPool is a naive lazy-failover client pool: Get() returns the next
+// healthy endpoint's connection in round-robin order, dialing it if
+// necessary, and connDown marks
+// an endpoint unhealthy so the next Get() call skips it until the health
+// checker goroutine restores it.
+type systemaPool struct {
+	mu        sync.Mutex
+	endpoints []*systemaEndpoint
+	next      int
+}
+
+// newSystemaPool builds a pool from a comma-separated list of "host:port"
+// endpoints, as configured via the SYSTEMA_HOSTS environment variable.
+func newSystemaPool(hosts string) *systemaPool {
+	p := &systemaPool{}
+	for _, addr := range strings.Split(hosts, ",") {
+		addr = strings.TrimSpace(addr)
+		if addr != "" {
+			p.endpoints = append(p.endpoints, &systemaEndpoint{addr: addr})
+		}
+	}
+	return p
+}
+
+// systemaDialOptions are the dial options every connection to a SystemA
+// endpoint must use, whether dialed from Get() or redialed by the health
+// checker when restoring a previously-evicted endpoint. Sharing this list
+// keeps the chunk0-4 error-translation interceptors in effect regardless of
+// which code path established the connection.
+func systemaDialOptions() []grpc.DialOption {
+	return []grpc.DialOption{
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithUnaryInterceptor(interceptors.UnaryClientInterceptor),
+		grpc.WithStreamInterceptor(interceptors.StreamClientInterceptor),
+	}
+}
+
+// dial lazily connects to an endpoint if it doesn't already have a live
+// connection.
+func (p *systemaPool) dial(e *systemaEndpoint) (*grpc.ClientConn, error) {
+	if e.conn != nil {
+		return e.conn, nil
+	}
+	conn, err := grpc.NewClient(e.addr, systemaDialOptions()...)
+	if err != nil {
+		return nil, err
+	}
+	e.conn = conn
+	return conn, nil
+}
+
+// Get returns a connection to the next healthy endpoint, dialing it lazily
+// if needed. It round-robins among healthy endpoints so load isn't pinned
+// to a single SystemA instance.
+func (p *systemaPool) Get() (*grpc.ClientConn, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if len(p.endpoints) == 0 {
+		return nil, errors.New("systema: no endpoints configured")
+	}
+	for i := 0; i < len(p.endpoints); i++ {
+		idx := (p.next + i) % len(p.endpoints)
+		e := p.endpoints[idx]
+		if e.unhealthy {
+			continue
+		}
+		conn, err := p.dial(e)
+		if err != nil {
+			p.markDownLocked(e)
+			continue
+		}
+		p.next = idx + 1
+		return conn, nil
+	}
+	return nil, errors.New("systema: all endpoints unavailable")
+}
+
+// Done releases a connection acquired via Get. The naive pool doesn't
+// refcount connections, so this is a no-op kept for compatibility with
+// callers that acquire/release around each SystemA call.
+func (p *systemaPool) Done() error {
+	return nil
+}
+
+// evictConn evicts conn from pool if pool supports it, and is a no-op
+// otherwise. This keeps the systema-gc reap paths from hard-depending on
+// mgr.systema being a *systemaPool specifically.
+func evictConn(pool interface{ Get() (*grpc.ClientConn, error) }, conn *grpc.ClientConn) {
+	if down, ok := pool.(systemaFailoverPool); ok {
+		down.connDown(conn)
+	}
+}
+
+// systemaFailoverPool is implemented by systema client pools that support
+// evicting a dead connection, such as systemaPool. mgr.systema only needs to
+// satisfy this interface to get failover behavior; pools that don't are used
+// exactly as they were before this change.
+type systemaFailoverPool interface {
+	connDown(conn *grpc.ClientConn)
+}
+
+// connDown evicts the connection backing conn, marking its endpoint
+// unhealthy for systemaUnhealthyCooldown so the next Get() call skips it
+// and dials a fresh connection to a different endpoint instead.
+func (p *systemaPool) connDown(conn *grpc.ClientConn) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for _, e := range p.endpoints {
+		if e.conn == conn {
+			p.markDownLocked(e)
+			return
+		}
+	}
+}
+
+func (p *systemaPool) markDownLocked(e *systemaEndpoint) {
+	if e.conn != nil {
+		_ = e.conn.Close()
+		e.conn = nil
+	}
+	e.unhealthy = true
+	e.evictedAt = time.Now()
+}
+
+// healthCheck runs until ctx is cancelled, periodically probing evicted
+// endpoints via grpc_health_v1 and restoring them once they respond healthy
+// again.
+func (p *systemaPool) healthCheck(ctx context.Context) {
+	ticker := time.NewTicker(systemaUnhealthyCooldown)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			p.probeUnhealthy(ctx)
+		}
+	}
+}
+
+func (p *systemaPool) probeUnhealthy(ctx context.Context) {
+	p.mu.Lock()
+	candidates := make([]*systemaEndpoint, 0, len(p.endpoints))
+	for _, e := range p.endpoints {
+		if e.unhealthy && time.Since(e.evictedAt) >= systemaUnhealthyCooldown {
+			candidates = append(candidates, e)
+		}
+	}
+	p.mu.Unlock()
+
+	for _, e := range candidates {
+		conn, err := grpc.NewClient(e.addr, systemaDialOptions()...)
+		if err != nil {
+			continue
+		}
+		hctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+		resp, err := grpc_health_v1.NewHealthClient(conn).Check(hctx, &grpc_health_v1.HealthCheckRequest{})
+		cancel()
+		if err == nil && resp.GetStatus() != grpc_health_v1.HealthCheckResponse_SERVING {
+			err = errors.New("health status " + resp.GetStatus().String())
+		}
+		if err != nil {
+			_ = conn.Close()
+			dlog.Debugf(ctx, "systema: health probe for %s still failing: %v", e.addr, err)
+			continue
+		}
+
+		p.mu.Lock()
+		e.conn = conn
+		e.unhealthy = false
+		p.mu.Unlock()
+		dlog.Infof(ctx, "systema: endpoint %s restored", e.addr)
+	}
+}
+
+// isConnDown classifies a gRPC error as indicating the connection itself is
+// dead, as opposed to a normal application-level RPC failure.
+func isConnDown(err error) bool {
+	if err == nil {
+		return false
+	}
+	if errors.Is(err, io.EOF) || errors.Is(err, interceptors.ErrSystemAUnavailable) {
+		return true
+	}
+	return status.Code(err) == codes.Unavailable
+}
@@ -0,0 +1,75 @@
+package manager
+
+import (
+	"testing"
+
+	"google.golang.org/grpc"
+)
+
+func TestSystemaPoolGetRoundRobins(t *testing.T) {
+	p := newSystemaPool("h1:1,h2:2,h3:3")
+
+	var seen []*grpc.ClientConn
+	for i := 0; i < 3; i++ {
+		conn, err := p.Get()
+		if err != nil {
+			t.Fatalf("Get() #%d: %v", i, err)
+		}
+		seen = append(seen, conn)
+	}
+	if seen[0] == seen[1] || seen[1] == seen[2] || seen[0] == seen[2] {
+		t.Fatalf("expected 3 distinct connections, got %v", seen)
+	}
+
+	fourth, err := p.Get()
+	if err != nil {
+		t.Fatalf("Get() #4: %v", err)
+	}
+	if fourth != seen[0] {
+		t.Errorf("round-robin didn't wrap: Get() #4 = %v, want %v (same as #1)", fourth, seen[0])
+	}
+}
+
+func TestSystemaPoolConnDownEvictsEndpoint(t *testing.T) {
+	p := newSystemaPool("h1:1,h2:2")
+
+	first, err := p.Get()
+	if err != nil {
+		t.Fatalf("Get(): %v", err)
+	}
+	p.connDown(first)
+
+	for i := 0; i < 4; i++ {
+		conn, err := p.Get()
+		if err != nil {
+			t.Fatalf("Get() after connDown, iteration %d: %v", i, err)
+		}
+		if conn == first {
+			t.Fatalf("Get() returned the evicted connection")
+		}
+	}
+}
+
+func TestSystemaPoolGetErrorsWhenAllUnhealthy(t *testing.T) {
+	p := newSystemaPool("h1:1")
+
+	conn, err := p.Get()
+	if err != nil {
+		t.Fatalf("Get(): %v", err)
+	}
+	p.connDown(conn)
+
+	if _, err := p.Get(); err == nil {
+		t.Fatal("expected Get() to fail once the only endpoint is unhealthy")
+	}
+}
+
+func TestEvictConnIgnoresPoolsWithoutFailoverSupport(t *testing.T) {
+	// A pool that doesn't implement systemaFailoverPool must be left alone
+	// rather than panicking or erroring.
+	evictConn(&noFailoverPool{}, nil)
+}
+
+type noFailoverPool struct{}
+
+func (*noFailoverPool) Get() (*grpc.ClientConn, error) { return nil, nil }
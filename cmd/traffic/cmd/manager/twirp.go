@@ -0,0 +1,95 @@
+package manager
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	rpc "github.com/telepresenceio/telepresence/rpc/v2/manager"
+)
+
+// twirpPrefix is the path prefix under which the Twirp transport is mounted
+// on the h2c handler, for clients that cannot speak HTTP/2 or gRPC.
+const twirpPrefix = "/twirp/"
+
+// twirpServer adapts the Manager's ManagerServer implementation to Twirp, so
+// unary RPCs are reachable over plain HTTP/1.1. The streaming RPCs
+// (WatchAgents, WatchIntercepts) aren't unary and are instead exposed as
+// long-poll endpoints backed by sw's caches, returning JSON snapshots with a
+// resume_token the caller echoes back to resume from where it left off,
+// since Twirp has no concept of server streams.
+type twirpServer struct {
+	sw *stateWatcher
+}
+
+// newTwirpHandler builds the combined Twirp + long-poll handler backing
+// /twirp/, dispatching unary RPCs to the same mgr instance as the gRPC
+// server, and long-poll watches to sw, so intercept state remains
+// single-sourced.
+func newTwirpHandler(mgr *Manager, sw *stateWatcher) http.Handler {
+	ts := &twirpServer{sw: sw}
+	twirpHandler := rpc.NewManagerServerTwirp(mgr)
+
+	mux := http.NewServeMux()
+	mux.Handle(rpc.ManagerPathPrefix, twirpHandler)
+	mux.HandleFunc(twirpPrefix+"WatchAgents", ts.longPollWatchAgents)
+	mux.HandleFunc(twirpPrefix+"WatchIntercepts", ts.longPollWatchIntercepts)
+	return mux
+}
+
+// agentSnapshot is the long-poll response body for WatchAgents: the set of
+// agents as of this snapshot, plus an opaque ResumeToken the caller echoes
+// back via ?resume_token= on its next request.
+type agentSnapshot struct {
+	Agents      []*rpc.AgentInfo `json:"agents"`
+	ResumeToken string           `json:"resume_token"`
+}
+
+// interceptSnapshot is the WatchIntercepts equivalent of agentSnapshot.
+type interceptSnapshot struct {
+	Intercepts  []*rpc.InterceptInfo `json:"intercepts"`
+	ResumeToken string               `json:"resume_token"`
+}
+
+// longPollWatchAgents returns the current agent snapshot immediately if the
+// caller's resume_token is behind the cache's sequence, or blocks until the
+// next update otherwise. Either way the response's resume_token is the
+// cache's current sequence, so a caller that keeps polling never misses an
+// update even if it's slower than the change rate.
+func (ts *twirpServer) longPollWatchAgents(w http.ResponseWriter, r *http.Request) {
+	agents, seq, err := ts.sw.agents.waitAfter(r.Context(), resumeSeq(r))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusGatewayTimeout)
+		return
+	}
+	writeJSON(w, agentSnapshot{Agents: agents, ResumeToken: strconv.FormatUint(seq, 10)})
+}
+
+// longPollWatchIntercepts is the WatchIntercepts equivalent of
+// longPollWatchAgents.
+func (ts *twirpServer) longPollWatchIntercepts(w http.ResponseWriter, r *http.Request) {
+	intercepts, seq, err := ts.sw.intercepts.waitAfter(r.Context(), resumeSeq(r))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusGatewayTimeout)
+		return
+	}
+	writeJSON(w, interceptSnapshot{Intercepts: intercepts, ResumeToken: strconv.FormatUint(seq, 10)})
+}
+
+// resumeSeq parses the resume_token query parameter as the sequence number
+// the caller last saw. A missing or malformed token is treated as 0, i.e.
+// "I've never seen a snapshot", so the first request returns immediately
+// with whatever's current once the cache holds at least one snapshot; before
+// that it blocks until the first one arrives.
+func resumeSeq(r *http.Request) uint64 {
+	seq, err := strconv.ParseUint(r.URL.Query().Get("resume_token"), 10, 64)
+	if err != nil {
+		return 0
+	}
+	return seq
+}
+
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(v)
+}
@@ -0,0 +1,199 @@
+package manager
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	rpc "github.com/telepresenceio/telepresence/rpc/v2/manager"
+)
+
+// entityTTL approximates how long an agent or intercept may go unseen on
+// its watch stream before the expiry wheel considers it due for a GC
+// sweep. It is a fixed value (three times the old 5-second ticker interval),
+// not an authoritative keepalive timeout owned by the state layer.
+const entityTTL = 15 * time.Second
+
+// agentCache holds the latest known set of agents, version-tagged so the
+// Twirp long-poll WatchAgents endpoint can tell whether a caller's
+// resume_token is already stale: if the cache's sequence has moved past what
+// the caller last saw, it returns the current snapshot immediately instead
+// of blocking, so a slow poller can't silently miss updates.
+type agentCache struct {
+	mu      sync.Mutex
+	agents  []*rpc.AgentInfo
+	seq     uint64
+	changed chan struct{}
+}
+
+func newAgentCache() *agentCache {
+	return &agentCache{changed: make(chan struct{})}
+}
+
+func (c *agentCache) set(agents []*rpc.AgentInfo) {
+	c.mu.Lock()
+	c.agents = agents
+	c.seq++
+	ch := c.changed
+	c.changed = make(chan struct{})
+	c.mu.Unlock()
+	close(ch)
+}
+
+// waitAfter returns immediately with the current snapshot if it's newer than
+// lastSeq, otherwise blocks until the next update or ctx is cancelled.
+func (c *agentCache) waitAfter(ctx context.Context, lastSeq uint64) ([]*rpc.AgentInfo, uint64, error) {
+	for {
+		c.mu.Lock()
+		if c.seq != lastSeq {
+			agents, seq := c.agents, c.seq
+			c.mu.Unlock()
+			return agents, seq, nil
+		}
+		ch := c.changed
+		c.mu.Unlock()
+
+		select {
+		case <-ch:
+		case <-ctx.Done():
+			return nil, lastSeq, ctx.Err()
+		}
+	}
+}
+
+// interceptCache is the WatchIntercepts counterpart of agentCache.
+type interceptCache struct {
+	mu         sync.Mutex
+	intercepts []*rpc.InterceptInfo
+	seq        uint64
+	changed    chan struct{}
+}
+
+func newInterceptCache() *interceptCache {
+	return &interceptCache{changed: make(chan struct{})}
+}
+
+func (c *interceptCache) set(intercepts []*rpc.InterceptInfo) {
+	c.mu.Lock()
+	c.intercepts = intercepts
+	c.seq++
+	ch := c.changed
+	c.changed = make(chan struct{})
+	c.mu.Unlock()
+	close(ch)
+}
+
+func (c *interceptCache) waitAfter(ctx context.Context, lastSeq uint64) ([]*rpc.InterceptInfo, uint64, error) {
+	for {
+		c.mu.Lock()
+		if c.seq != lastSeq {
+			intercepts, seq := c.intercepts, c.seq
+			c.mu.Unlock()
+			return intercepts, seq, nil
+		}
+		ch := c.changed
+		c.mu.Unlock()
+
+		select {
+		case <-ch:
+		case <-ctx.Done():
+			return nil, lastSeq, ctx.Err()
+		}
+	}
+}
+
+// dueForSweep pops every entry that's timed out on wheel as of now and
+// returns how many there were. intercept-gc uses this to decide whether to
+// call mgr.expire() and to drive the interceptGCSweeps counter, so the timer
+// actually consumes wheel.expired() rather than only using isEmpty() as a
+// gate that never inspects deadlines.
+func dueForSweep(wheel *expiryWheel, now time.Time) int {
+	return len(wheel.expired(now))
+}
+
+// stateWatcher drains the manager's agent and intercept watch streams once
+// and is the single place that keeps the expiry wheel's deadlines, and the
+// Twirp long-poll caches, in sync with what's actually connected, instead of
+// each consumer needing its own subscription.
+type stateWatcher struct {
+	wheel *expiryWheel
+	mets  *metrics
+
+	agents     *agentCache
+	intercepts *interceptCache
+}
+
+func newStateWatcher(wheel *expiryWheel, mets *metrics) *stateWatcher {
+	return &stateWatcher{
+		wheel:      wheel,
+		mets:       mets,
+		agents:     newAgentCache(),
+		intercepts: newInterceptCache(),
+	}
+}
+
+// run drains mgr's watch streams until ctx is cancelled.
+func (sw *stateWatcher) run(ctx context.Context, mgr *Manager) {
+	go sw.watchAgents(ctx, mgr)
+	sw.watchIntercepts(ctx, mgr)
+}
+
+func (sw *stateWatcher) watchAgents(ctx context.Context, mgr *Manager) {
+	seen := map[string]bool{}
+	for snapshot := range mgr.state.WatchAgents(ctx, nil) {
+		agents := snapshot.State
+		sw.agents.set(agents)
+		sw.mets.agentsConnected.Set(float64(len(agents)))
+		now := time.Now()
+
+		live := make(map[string]bool, len(agents))
+		for _, a := range agents {
+			live[a.Id] = true
+			sw.wheel.touch("agent:"+a.Id, now.Add(entityTTL))
+		}
+		for id := range seen {
+			if !live[id] {
+				sw.wheel.remove("agent:" + id)
+			}
+		}
+		seen = live
+	}
+}
+
+func (sw *stateWatcher) watchIntercepts(ctx context.Context, mgr *Manager) {
+	current := map[string]*rpc.InterceptInfo{}
+	prevDispositions := map[string]bool{}
+
+	for snapshot := range mgr.state.WatchIntercepts(ctx, nil) {
+		now := time.Now()
+		for _, update := range snapshot.Updates {
+			key := "intercept:" + update.Value.Id
+			if update.Delete {
+				delete(current, update.Value.Id)
+				sw.wheel.remove(key)
+				continue
+			}
+			current[update.Value.Id] = update.Value
+			sw.wheel.touch(key, now.Add(entityTTL))
+		}
+
+		intercepts := make([]*rpc.InterceptInfo, 0, len(current))
+		counts := map[string]float64{}
+		for _, ii := range current {
+			intercepts = append(intercepts, ii)
+			counts[ii.Disposition.String()]++
+		}
+		sw.intercepts.set(intercepts)
+
+		for disposition := range prevDispositions {
+			if _, ok := counts[disposition]; !ok {
+				sw.mets.interceptsActive.WithLabelValues(disposition).Set(0)
+			}
+		}
+		prevDispositions = make(map[string]bool, len(counts))
+		for disposition, count := range counts {
+			sw.mets.interceptsActive.WithLabelValues(disposition).Set(count)
+			prevDispositions[disposition] = true
+		}
+	}
+}
@@ -0,0 +1,96 @@
+package manager
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	rpc "github.com/telepresenceio/telepresence/rpc/v2/manager"
+)
+
+func newResumeRequest(t *testing.T, token string) *http.Request {
+	t.Helper()
+	r := httptest.NewRequest(http.MethodGet, "/twirp/WatchAgents", nil)
+	if token != "" {
+		q := r.URL.Query()
+		q.Set("resume_token", token)
+		r.URL.RawQuery = q.Encode()
+	}
+	return r
+}
+
+func TestAgentCacheWaitAfterReturnsImmediatelyWhenStale(t *testing.T) {
+	c := newAgentCache()
+	c.set([]*rpc.AgentInfo{{Id: "a1"}})
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	agents, seq, err := c.waitAfter(ctx, 0)
+	if err != nil {
+		t.Fatalf("waitAfter: %v", err)
+	}
+	if seq != 1 || len(agents) != 1 || agents[0].Id != "a1" {
+		t.Fatalf("waitAfter(0) = %v, %d, want the a1 snapshot at seq 1", agents, seq)
+	}
+}
+
+func TestAgentCacheWaitAfterBlocksUntilNextSet(t *testing.T) {
+	c := newAgentCache()
+	c.set([]*rpc.AgentInfo{{Id: "a1"}})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	done := make(chan struct{})
+	var gotSeq uint64
+	go func() {
+		defer close(done)
+		_, seq, err := c.waitAfter(ctx, 1)
+		if err != nil {
+			t.Errorf("waitAfter: %v", err)
+		}
+		gotSeq = seq
+	}()
+
+	time.Sleep(20 * time.Millisecond)
+	c.set([]*rpc.AgentInfo{{Id: "a1"}, {Id: "a2"}})
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("waitAfter never returned after set() was called")
+	}
+	if gotSeq != 2 {
+		t.Fatalf("waitAfter returned seq %d, want 2", gotSeq)
+	}
+}
+
+func TestAgentCacheWaitAfterRespectsContextCancellation(t *testing.T) {
+	c := newAgentCache()
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if _, _, err := c.waitAfter(ctx, 0); err == nil {
+		t.Fatal("expected waitAfter to return an error for an already-cancelled context")
+	}
+}
+
+func TestResumeSeqParsesOrDefaultsToZero(t *testing.T) {
+	cases := map[string]uint64{
+		"":      0,
+		"abc":   0,
+		"-1":    0,
+		"0":     0,
+		"42":    42,
+		"99999": 99999,
+	}
+	for token, want := range cases {
+		r := newResumeRequest(t, token)
+		if got := resumeSeq(r); got != want {
+			t.Errorf("resumeSeq(%q) = %d, want %d", token, got, want)
+		}
+	}
+}
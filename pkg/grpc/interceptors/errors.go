@@ -0,0 +1,75 @@
+// Package interceptors provides gRPC client and server interceptors that
+// translate between internal typed errors and gRPC status errors, mirroring
+// the FromGRPC/ToGRPC split used at both ends of the Manager<->SystemA
+// connection.
+package interceptors
+
+import (
+	"errors"
+	"fmt"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Typed errors returned by the client interceptor once a SystemA status
+// error has been classified. Callers on the reap paths switch on these
+// instead of treating every RPC failure identically.
+var (
+	ErrSystemAUnavailable = errors.New("systema: unavailable")
+	ErrSystemATimeout     = errors.New("systema: timed out")
+	ErrSystemAAuth        = errors.New("systema: authentication failed")
+	ErrInterceptGone      = errors.New("systema: intercept already gone")
+)
+
+// FromGRPC converts a status.Status error returned by a SystemA RPC into one
+// of the typed errors above. Errors that don't match a known code are
+// returned unwrapped.
+//
+// codes.DeadlineExceeded is deliberately kept distinct from
+// codes.Unavailable: a single slow-but-healthy RPC timing out is not the
+// same signal as the connection itself being down, and callers like
+// isConnDown need to tell them apart rather than evicting a good endpoint
+// because one call was slow.
+func FromGRPC(err error) error {
+	if err == nil {
+		return nil
+	}
+	st, ok := status.FromError(err)
+	if !ok {
+		return err
+	}
+	switch st.Code() {
+	case codes.Unavailable:
+		return fmt.Errorf("%w: %s", ErrSystemAUnavailable, st.Message())
+	case codes.DeadlineExceeded:
+		return fmt.Errorf("%w: %s", ErrSystemATimeout, st.Message())
+	case codes.Unauthenticated, codes.PermissionDenied:
+		return fmt.Errorf("%w: %s", ErrSystemAAuth, st.Message())
+	case codes.NotFound:
+		return fmt.Errorf("%w: %s", ErrInterceptGone, st.Message())
+	default:
+		return err
+	}
+}
+
+// ToGRPC converts an internal typed error into a gRPC status error with a
+// code and message appropriate for the other end of the connection. Errors
+// that don't match a known sentinel are passed through unchanged, so
+// handlers that already return a status error aren't double-wrapped.
+func ToGRPC(err error) error {
+	switch {
+	case err == nil:
+		return nil
+	case errors.Is(err, ErrSystemAUnavailable):
+		return status.Error(codes.Unavailable, err.Error())
+	case errors.Is(err, ErrSystemATimeout):
+		return status.Error(codes.DeadlineExceeded, err.Error())
+	case errors.Is(err, ErrSystemAAuth):
+		return status.Error(codes.Unauthenticated, err.Error())
+	case errors.Is(err, ErrInterceptGone):
+		return status.Error(codes.NotFound, err.Error())
+	default:
+		return err
+	}
+}
@@ -0,0 +1,53 @@
+package interceptors
+
+import (
+	"errors"
+	"testing"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestFromGRPCClassification(t *testing.T) {
+	cases := []struct {
+		code codes.Code
+		want error
+	}{
+		{codes.Unavailable, ErrSystemAUnavailable},
+		{codes.DeadlineExceeded, ErrSystemATimeout},
+		{codes.Unauthenticated, ErrSystemAAuth},
+		{codes.PermissionDenied, ErrSystemAAuth},
+		{codes.NotFound, ErrInterceptGone},
+	}
+	for _, c := range cases {
+		err := FromGRPC(status.Error(c.code, "boom"))
+		if !errors.Is(err, c.want) {
+			t.Errorf("FromGRPC(%s) = %v, want errors.Is(_, %v)", c.code, err, c.want)
+		}
+	}
+}
+
+func TestFromGRPCDeadlineExceededIsNotUnavailable(t *testing.T) {
+	err := FromGRPC(status.Error(codes.DeadlineExceeded, "slow"))
+	if errors.Is(err, ErrSystemAUnavailable) {
+		t.Errorf("FromGRPC(DeadlineExceeded) must not classify as ErrSystemAUnavailable, got %v", err)
+	}
+}
+
+func TestToGRPCRoundTrip(t *testing.T) {
+	cases := []struct {
+		err  error
+		code codes.Code
+	}{
+		{ErrSystemAUnavailable, codes.Unavailable},
+		{ErrSystemATimeout, codes.DeadlineExceeded},
+		{ErrSystemAAuth, codes.Unauthenticated},
+		{ErrInterceptGone, codes.NotFound},
+	}
+	for _, c := range cases {
+		st, ok := status.FromError(ToGRPC(c.err))
+		if !ok || st.Code() != c.code {
+			t.Errorf("ToGRPC(%v) code = %v, want %v", c.err, st.Code(), c.code)
+		}
+	}
+}
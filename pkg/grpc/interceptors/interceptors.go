@@ -0,0 +1,43 @@
+package interceptors
+
+import (
+	"context"
+
+	"google.golang.org/grpc"
+)
+
+// UnaryClientInterceptor runs FromGRPC on the error returned by every unary
+// call, so callers see the typed sentinel errors instead of raw
+// status.Status values.
+func UnaryClientInterceptor(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+	return FromGRPC(invoker(ctx, method, req, reply, cc, opts...))
+}
+
+// StreamClientInterceptor runs FromGRPC on the error returned when opening a
+// stream. Errors surfaced later, from Stream.RecvMsg, are the caller's
+// responsibility to translate since this interceptor only wraps stream
+// establishment.
+func StreamClientInterceptor(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+	stream, err := streamer(ctx, desc, cc, method, opts...)
+	if err != nil {
+		return nil, FromGRPC(err)
+	}
+	return stream, nil
+}
+
+// UnaryServerInterceptor runs ToGRPC on the error returned by the handler,
+// so internal typed errors are translated into the appropriate codes.* and
+// message before they reach the wire.
+func UnaryServerInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	resp, err := handler(ctx, req)
+	if err != nil {
+		return resp, ToGRPC(err)
+	}
+	return resp, nil
+}
+
+// StreamServerInterceptor is the streaming counterpart of
+// UnaryServerInterceptor.
+func StreamServerInterceptor(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	return ToGRPC(handler(srv, ss))
+}